@@ -0,0 +1,216 @@
+// Package alpm wraps Jguer/go-alpm/v2 so the rest of ghostbrew can query
+// the local pacman database and compare versions without shelling out to
+// pacman and string-parsing its output.
+package alpm
+
+import (
+	alpm "github.com/Jguer/go-alpm/v2"
+)
+
+const (
+	rootDir = "/"
+	dbPath  = "/var/lib/pacman"
+)
+
+// Package is the subset of alpm package metadata ghostbrew cares about.
+type Package struct {
+	Name     string
+	Version  string
+	Repo     string // sync db name, or "local" for packages not tied to one
+}
+
+// withHandle opens the local alpm handle, runs fn, and releases it
+// afterwards. Every exported function in this package goes through it
+// so callers never have to manage the handle's lifetime themselves.
+func withHandle(fn func(h *alpm.Handle) error) error {
+	h, err := alpm.Initialize(rootDir, dbPath)
+	if err != nil {
+		return err
+	}
+	defer h.Release()
+	return fn(h)
+}
+
+// LocalPackages returns every package recorded in the local pacman
+// database, i.e. everything `pacman -Q` would list.
+func LocalPackages() ([]Package, error) {
+	var pkgs []Package
+	err := withHandle(func(h *alpm.Handle) error {
+		db, err := h.LocalDB()
+		if err != nil {
+			return err
+		}
+		return db.PkgCache().ForEach(func(p alpm.IPackage) error {
+			pkgs = append(pkgs, Package{Name: p.Name(), Version: p.Version(), Repo: "local"})
+			return nil
+		})
+	})
+	return pkgs, err
+}
+
+// SyncPackages returns every package available across all configured
+// sync repos (core, extra, chaotic-aur, ...).
+func SyncPackages() ([]Package, error) {
+	var pkgs []Package
+	err := withHandle(func(h *alpm.Handle) error {
+		dbs, err := h.SyncDBs()
+		if err != nil {
+			return err
+		}
+		return dbs.ForEach(func(db alpm.IDB) error {
+			return db.PkgCache().ForEach(func(p alpm.IPackage) error {
+				pkgs = append(pkgs, Package{Name: p.Name(), Version: p.Version(), Repo: db.Name()})
+				return nil
+			})
+		})
+	})
+	return pkgs, err
+}
+
+// ForeignPackages returns locally installed packages that aren't present
+// in any sync db, i.e. exactly what `pacman -Qm` lists (AUR/Chaotic-AUR
+// installs, local builds, etc.).
+func ForeignPackages() ([]Package, error) {
+	var foreign []Package
+	err := withHandle(func(h *alpm.Handle) error {
+		localDB, err := h.LocalDB()
+		if err != nil {
+			return err
+		}
+		dbs, err := h.SyncDBs()
+		if err != nil {
+			return err
+		}
+		return localDB.PkgCache().ForEach(func(p alpm.IPackage) error {
+			inSync := false
+			_ = dbs.ForEach(func(db alpm.IDB) error {
+				if db.Pkg(p.Name()) != nil {
+					inSync = true
+				}
+				return nil
+			})
+			if !inSync {
+				foreign = append(foreign, Package{Name: p.Name(), Version: p.Version(), Repo: "local"})
+			}
+			return nil
+		})
+	})
+	return foreign, err
+}
+
+
+// Upgrade describes a pending version bump for an installed package.
+type Upgrade struct {
+	Name    string
+	OldVer  string
+	NewVer  string
+	Repo    string
+}
+
+// UpgradablePackages compares every locally installed, non-foreign
+// package against the sync dbs and returns the ones with a newer
+// version available, i.e. what `pacman -Qu` would list.
+func UpgradablePackages() ([]Upgrade, error) {
+	var upgrades []Upgrade
+	err := withHandle(func(h *alpm.Handle) error {
+		localDB, err := h.LocalDB()
+		if err != nil {
+			return err
+		}
+		dbs, err := h.SyncDBs()
+		if err != nil {
+			return err
+		}
+		return localDB.PkgCache().ForEach(func(local alpm.IPackage) error {
+			var newest alpm.IPackage
+			var newestRepo string
+			_ = dbs.ForEach(func(db alpm.IDB) error {
+				if p := db.Pkg(local.Name()); p != nil {
+					if newest == nil || alpm.VerCmp(p.Version(), newest.Version()) > 0 {
+						newest, newestRepo = p, db.Name()
+					}
+				}
+				return nil
+			})
+			if newest != nil && alpm.VerCmp(local.Version(), newest.Version()) < 0 {
+				upgrades = append(upgrades, Upgrade{
+					Name:   local.Name(),
+					OldVer: local.Version(),
+					NewVer: newest.Version(),
+					Repo:   newestRepo,
+				})
+			}
+			return nil
+		})
+	})
+	return upgrades, err
+}
+
+// FindSync looks up a package in the local db and, failing that, any
+// sync db, returning ok=false if neither has it. This is how the
+// resolver checks whether a dependency is already satisfied before
+// deciding it needs to be fetched from the AUR.
+func FindSync(name string) (pkg Package, ok bool, err error) {
+	err = withHandle(func(h *alpm.Handle) error {
+		localDB, derr := h.LocalDB()
+		if derr != nil {
+			return derr
+		}
+		if p := localDB.Pkg(name); p != nil {
+			pkg, ok = Package{Name: p.Name(), Version: p.Version(), Repo: "local"}, true
+			return nil
+		}
+		dbs, derr := h.SyncDBs()
+		if derr != nil {
+			return derr
+		}
+		return dbs.ForEach(func(db alpm.IDB) error {
+			if ok {
+				return nil
+			}
+			if p := db.Pkg(name); p != nil {
+				pkg, ok = Package{Name: p.Name(), Version: p.Version(), Repo: db.Name()}, true
+			}
+			return nil
+		})
+	})
+	return pkg, ok, err
+}
+
+// ProvidesSync reports whether any locally installed or sync-db package
+// provides name (via its `provides=` array), satisfying a dependency
+// that isn't available under its own name.
+func ProvidesSync(name string) (bool, error) {
+	provided := false
+	err := withHandle(func(h *alpm.Handle) error {
+		localDB, derr := h.LocalDB()
+		if derr != nil {
+			return derr
+		}
+		if localDB.PkgCache().FindSatisfier(name) != nil {
+			provided = true
+			return nil
+		}
+		dbs, derr := h.SyncDBs()
+		if derr != nil {
+			return derr
+		}
+		return dbs.ForEach(func(db alpm.IDB) error {
+			if provided {
+				return nil
+			}
+			if db.PkgCache().FindSatisfier(name) != nil {
+				provided = true
+			}
+			return nil
+		})
+	})
+	return provided, err
+}
+
+// VerCmp compares two version strings the way libalpm does (honoring
+// epoch, pkgver, and pkgrel ordering), returning <0, 0, or >0 the same
+// way strcmp/alpm_pkg_vercmp would.
+func VerCmp(a, b string) int {
+	return alpm.VerCmp(a, b)
+}
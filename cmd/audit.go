@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/GhostKellz/ghostbrew/pgp"
+)
+
+// auditCmd inspects a PKGBUILD and its validpgpkeys without building it.
+var auditCmd = &cobra.Command{
+	Use:   "audit <pkg>",
+	Short: "Inspect a PKGBUILD for risky commands and unverified PGP keys",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pkg := args[0]
+		inspectPKGBUILD(pkg)
+
+		info, _, err := fetchSRCINFO(pkg, InstallOptions{})
+		if err != nil {
+			fmt.Printf("[AUDIT] Failed to fetch .SRCINFO for %s: %v\n", pkg, err)
+			return
+		}
+		if len(info.ValidPGPKeys) == 0 {
+			return
+		}
+		if err := pgp.CheckKeys(info.Pkgbase, info.ValidPGPKeys, false); err != nil {
+			fmt.Printf("[AUDIT] PGP key check failed: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+}
@@ -2,19 +2,64 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/GhostKellz/ghostbrew/alpm"
+	"github.com/GhostKellz/ghostbrew/internal/aur"
 )
 
 var completionCmd = &cobra.Command{
-	Use:   "completion [bash|zsh|fish]",
-	Short: "Generate shell completions",
-	Args:  cobra.ExactArgs(1),
+	Use:       "completion [bash|zsh|fish]",
+	Short:     "Generate shell completions",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("[TODO] Shell completion for %s not yet implemented.\n", args[0])
+		var err error
+		switch args[0] {
+		case "bash":
+			err = rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			err = rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			err = rootCmd.GenFishCompletion(os.Stdout, true)
+		default:
+			err = fmt.Errorf("unsupported shell %q (want bash, zsh, or fish)", args[0])
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "completion:", err)
+			os.Exit(1)
+		}
 	},
 }
 
+// completePackageNames is a cobra.ValidArgsFunction shared by the
+// commands that take a <pkg> argument (info, install, search). It
+// completes from the cached AUR name list plus every package visible
+// across the configured sync repos (official + Chaotic-AUR), in place
+// of shelling out to `pacman -Slq` on every keystroke.
+func completePackageNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var names []string
+	if aurNames, err := aur.Names(); err == nil {
+		names = append(names, aurNames...)
+	}
+	if syncPkgs, err := alpm.SyncPackages(); err == nil {
+		for _, p := range syncPkgs {
+			names = append(names, p.Name)
+		}
+	}
+
+	var matches []string
+	for _, n := range names {
+		if strings.HasPrefix(n, toComplete) {
+			matches = append(matches, n)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
+
 func init() {
 	rootCmd.AddCommand(completionCmd)
 }
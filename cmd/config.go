@@ -10,6 +10,27 @@ type Config struct {
 	DefaultFlags    []string `yaml:"default_flags"`
 	AurPath         string   `yaml:"aur_path"`
 	IgnoredPackages []string `yaml:"ignored_packages"`
+	KeyServers      []string `yaml:"key_servers"`
+	// DiffMenu and NewsOnUpgrade are *bool, not bool: both default to
+	// on, and a config.yml that simply omits the key must decode to
+	// "unset" rather than the bool zero value, or every existing
+	// user's config would silently disable these prompts the moment
+	// they upgrade ghostbrew without having opted out.
+	DiffMenu      *bool `yaml:"diff_menu"`
+	NewsOnUpgrade *bool `yaml:"news_on_upgrade"`
+}
+
+// diffMenuEnabled reports whether cfg's diff_menu setting is on,
+// defaulting to true when the key is absent from config.yml (cfg is nil
+// when there's no config file at all).
+func (cfg *Config) diffMenuEnabled() bool {
+	return cfg == nil || cfg.DiffMenu == nil || *cfg.DiffMenu
+}
+
+// newsOnUpgradeEnabled reports whether cfg's news_on_upgrade setting is
+// on, defaulting to true when the key is absent from config.yml.
+func (cfg *Config) newsOnUpgradeEnabled() bool {
+	return cfg == nil || cfg.NewsOnUpgrade == nil || *cfg.NewsOnUpgrade
 }
 
 func LoadConfig() (*Config, error) {
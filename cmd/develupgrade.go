@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/GhostKellz/ghostbrew/alpm"
+	"github.com/GhostKellz/ghostbrew/devel"
+	"github.com/GhostKellz/ghostbrew/vcs"
+)
+
+// develUpgradeCmd checks every installed -git/-svn/-hg/-bzr/-darcs
+// package for upstream changes and rebuilds any whose tracked source
+// has moved, independent of the regular upgrade path's AUR Version
+// check.
+var develUpgradeCmd = &cobra.Command{
+	Use:   "devel-upgrade",
+	Short: "Rebuild installed VCS/devel packages whose upstream HEAD has moved",
+	Run: func(cmd *cobra.Command, args []string) {
+		noConfirm, _ := cmd.Flags().GetBool("no-confirm")
+
+		foreign, err := alpm.ForeignPackages()
+		if err != nil {
+			fmt.Println("Failed to query local package database:", err)
+			return
+		}
+
+		var toUpdate []string
+		for _, pkg := range foreign {
+			if !vcs.IsDevel(pkg.Name) {
+				continue
+			}
+			fmt.Printf("Checking %s for upstream changes...\n", pkg.Name)
+			changed, err := devel.CheckUpgrade(pkg.Name)
+			if err != nil {
+				fmt.Printf("[WARN] %s: %v\n", pkg.Name, err)
+				continue
+			}
+			if changed {
+				toUpdate = append(toUpdate, pkg.Name)
+			}
+		}
+
+		if len(toUpdate) == 0 {
+			fmt.Println("No devel packages have upstream changes.")
+			return
+		}
+		fmt.Printf("Devel packages to rebuild: %v\n", toUpdate)
+		InstallPackages(toUpdate, InstallOptions{Parallel: 2, NoConfirm: noConfirm})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(develUpgradeCmd)
+	develUpgradeCmd.Flags().Bool("no-confirm", false, "Do not prompt for confirmation")
+}
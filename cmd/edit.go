@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// editCmd opens a package's cached PKGBUILD in $VISUAL/$EDITOR without
+// building it, cloning the AUR git repo first if it isn't cached yet.
+var editCmd = &cobra.Command{
+	Use:   "edit <pkg>",
+	Short: "Open a package's PKGBUILD in $VISUAL/$EDITOR without building it",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pkg := args[0]
+		_, dir, err := fetchSRCINFO(pkg, InstallOptions{NoConfirm: true})
+		if err != nil {
+			fmt.Println("Failed to fetch", pkg+":", err)
+			return
+		}
+		if err := openInEditor(filepath.Join(dir, "PKGBUILD")); err != nil {
+			fmt.Println("Failed to open editor:", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
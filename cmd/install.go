@@ -4,27 +4,37 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
 )
 
 // installCmd represents the install command
 var installCmd = &cobra.Command{
-	Use:   "install [package]",
-	Short: "Install a package from official, Chaotic-AUR, or AUR",
-	Args:  cobra.ExactArgs(1),
+	Use:               "install [package]",
+	Short:             "Install a package from official, Chaotic-AUR, or AUR",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completePackageNames,
 	Run: func(cmd *cobra.Command, args []string) {
-		pkg := args[0]
-		err := InstallPackage(pkg)
-		if err != nil {
-			fmt.Printf("Failed to install '%s': %v\n", pkg, err)
-		} else {
-			fmt.Printf("Successfully installed '%s'!\n", pkg)
-		}
+		clean, _ := cmd.Flags().GetBool("clean-build")
+		noConfirm, _ := cmd.Flags().GetBool("noconfirm")
+		rmDeps, _ := cmd.Flags().GetBool("rmdeps")
+		diffs, _ := cmd.Flags().GetBool("diffs")
+		editMenu, _ := cmd.Flags().GetBool("editmenu")
+		InstallPackages(args, InstallOptions{
+			Parallel:   2,
+			CleanBuild: clean,
+			NoConfirm:  noConfirm,
+			RmDeps:     rmDeps,
+			Diffs:      diffs,
+			EditMenu:   editMenu,
+		})
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(installCmd)
+	installCmd.Flags().Bool("clean-build", false, "Remove any cached build directory before cloning")
+	installCmd.Flags().Bool("noconfirm", false, "Pass --noconfirm through to makepkg")
+	installCmd.Flags().Bool("rmdeps", false, "Remove make/check dependencies once the build finishes")
+	installCmd.Flags().Bool("diffs", false, "Force the PKGBUILD review menu even if diff_menu is disabled in config")
+	installCmd.Flags().Bool("editmenu", false, "Open the PKGBUILD in $VISUAL/$EDITOR before the review prompt")
 }
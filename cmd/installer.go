@@ -1,94 +1,184 @@
 package cmd
 
 import (
-	"encoding/json"
+	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
+
+	"github.com/GhostKellz/ghostbrew/dep"
+	"github.com/GhostKellz/ghostbrew/pgp"
+	"github.com/GhostKellz/ghostbrew/srcinfo"
+	"github.com/GhostKellz/ghostbrew/vcs"
 )
 
 type InstallOptions struct {
-	Parallel int // Number of parallel jobs
+	Parallel   int  // Number of parallel jobs
+	CleanBuild bool // rm -rf the build dir before cloning
+	NoConfirm  bool // pass --noconfirm through to makepkg; also skips the PKGBUILD review prompt
+	RmDeps     bool // pacman -Rns make/check deps once the build queue finishes
+	Diffs      bool // force the PKGBUILD review menu even if Config.DiffMenu is false
+	EditMenu   bool // open the PKGBUILD in $VISUAL/$EDITOR before the review prompt
 }
 
-// AURInfoCache caches AUR info responses
-var AURInfoCache = struct {
-	m map[string]map[string]interface{}
-	sync.Mutex
-}{m: make(map[string]map[string]interface{})}
-
-// fetchAURInfo fetches AUR info for a package (including dependencies), with cache
-func fetchAURInfo(pkg string) (map[string]interface{}, error) {
-	AURInfoCache.Lock()
-	if info, ok := AURInfoCache.m[pkg]; ok {
-		AURInfoCache.Unlock()
-		return info, nil
-	}
-	AURInfoCache.Unlock()
-	resp, err := http.Get("https://aur.archlinux.org/rpc/?v=5&type=info&arg=" + pkg)
+// buildCacheDir returns $XDG_CACHE_HOME/ghostbrew/build/<pkg>, creating
+// $XDG_CACHE_HOME/ghostbrew/build if it doesn't already exist.
+func buildCacheDir(pkg string) (string, error) {
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		cacheHome = filepath.Join(os.Getenv("HOME"), ".cache")
+	}
+	dir := filepath.Join(cacheHome, "ghostbrew", "build")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, pkg), nil
+}
+
+// fetchSRCINFO clones (or updates) the AUR git repo for pkg and parses
+// its .SRCINFO, returning the parsed metadata and the checkout dir.
+func fetchSRCINFO(pkg string, opts InstallOptions) (*srcinfo.SRCINFO, string, error) {
+	dir, err := buildCacheDir(pkg)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	defer resp.Body.Close()
-	var result struct {
-		Results []map[string]interface{}
+	if opts.CleanBuild {
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, "", fmt.Errorf("clean build dir for %s: %w", pkg, err)
+		}
+	}
+	firstBuild := false
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		fetch := exec.Command("git", "-C", dir, "fetch")
+		fetch.Stdout = os.Stdout
+		fetch.Stderr = os.Stderr
+		if err := fetch.Run(); err != nil {
+			return nil, "", fmt.Errorf("git fetch for %s: %w", pkg, err)
+		}
+	} else {
+		clone := exec.Command("git", "clone", "https://aur.archlinux.org/"+pkg+".git", dir)
+		clone.Stdout = os.Stdout
+		clone.Stderr = os.Stderr
+		if err := clone.Run(); err != nil {
+			return nil, "", fmt.Errorf("git clone for %s: %w", pkg, err)
+		}
+		firstBuild = true
+	}
+
+	cfg, _ := LoadConfig()
+	diffMenu := cfg.diffMenuEnabled()
+	if opts.Diffs {
+		diffMenu = true
+	}
+	skipPrompt := opts.NoConfirm || !diffMenu
+	if err := reviewPKGBUILD(dir, firstBuild, skipPrompt, opts.EditMenu); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", pkg, err)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || len(result.Results) == 0 {
-		return nil, fmt.Errorf("No info found for %s", pkg)
+	if !firstBuild {
+		merge := exec.Command("git", "-C", dir, "merge", "--ff-only", "origin/HEAD")
+		merge.Stdout = os.Stdout
+		merge.Stderr = os.Stderr
+		if err := merge.Run(); err != nil {
+			return nil, "", fmt.Errorf("git merge for %s: %w", pkg, err)
+		}
+	}
+
+	f, err := os.Open(filepath.Join(dir, ".SRCINFO"))
+	if err != nil {
+		return nil, "", fmt.Errorf("open .SRCINFO for %s: %w", pkg, err)
 	}
-	info := result.Results[0]
-	AURInfoCache.Lock()
-	AURInfoCache.m[pkg] = info
-	AURInfoCache.Unlock()
-	return info, nil
+	defer f.Close()
+	info, err := srcinfo.Parse(f)
+	if err != nil {
+		return nil, "", fmt.Errorf("parse .SRCINFO for %s: %w", pkg, err)
+	}
+	return info, dir, nil
 }
 
-// resolveDependencies concurrently resolves dependencies for a list of packages
-func resolveDependencies(pkgs []string, seen map[string]bool) ([]string, error) {
-	var order []string
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	for _, pkg := range pkgs {
-		if seen[pkg] {
+// buildLogFile creates (truncating) $XDG_CACHE_HOME/ghostbrew/build/<pkg>.log
+// for a worker's makepkg output, so a parallel build queue's interleaved
+// terminal output can still be inspected per-package afterwards.
+func buildLogFile(pkg string) (*os.File, error) {
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		cacheHome = filepath.Join(os.Getenv("HOME"), ".cache")
+	}
+	dir := filepath.Join(cacheHome, "ghostbrew", "build")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(filepath.Join(dir, pkg+".log"))
+}
+
+// prefixWriter prepends prefix to every line written through it, so a
+// parallel build queue's interleaved terminal output stays attributable
+// to the package that produced it.
+type prefixWriter struct {
+	prefix string
+	w      io.Writer
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	for _, line := range bytes.SplitAfter(b, []byte("\n")) {
+		if len(line) == 0 {
 			continue
 		}
-		seen[pkg] = true
-		wg.Add(1)
-		go func(pkg string) {
-			defer wg.Done()
-			info, err := fetchAURInfo(pkg)
-			if err != nil {
-				fmt.Println("Dependency fetch failed:", err)
-				return
-			}
-			var depOrder []string
-			if deps, ok := info["Depends"].([]interface{}); ok {
-				depNames := make([]string, 0)
-				for _, d := range deps {
-					if depStr, ok := d.(string); ok {
-						depNames = append(depNames, depStr)
-					}
-				}
-				depOrder, _ = resolveDependencies(depNames, seen)
-			}
-			mu.Lock()
-			order = append(order, depOrder...)
-			order = append(order, pkg)
-			mu.Unlock()
-		}(pkg)
+		if _, err := p.w.Write(append([]byte(p.prefix), line...)); err != nil {
+			return 0, err
+		}
 	}
-	wg.Wait()
-	return order, nil
+	return len(b), nil
 }
 
-// checkGPGKey checks and imports GPG keys if missing (stub)
-func checkGPGKey(pkg string) {
-	// TODO: Implement real GPG key check and import logic
-	fmt.Printf("[GPG] Checking keys for %s...\n", pkg)
+// buildPackage clones the AUR package, parses its .SRCINFO, checks and
+// imports any PGP keys it needs, and runs makepkg against the checkout.
+// install controls whether built packages are installed (-si) or just
+// built (-s). makepkg's output is teed to a per-package log file under
+// $XDG_CACHE_HOME/ghostbrew/build/<pkg>.log and prefixed on the
+// terminal, since a parallel build queue interleaves several packages'
+// output at once.
+func buildPackage(pkg string, install bool, opts InstallOptions) error {
+	info, dir, err := fetchSRCINFO(pkg, opts)
+	if err != nil {
+		return err
+	}
+	if err := pgp.CheckKeys(info.Pkgbase, info.ValidPGPKeys, opts.NoConfirm); err != nil {
+		return err
+	}
+	args := []string{"-s"}
+	if install {
+		args = []string{"-si"}
+	}
+	if opts.NoConfirm {
+		args = append(args, "--noconfirm")
+	}
+
+	logFile, err := buildLogFile(pkg)
+	if err != nil {
+		return fmt.Errorf("open build log for %s: %w", pkg, err)
+	}
+	defer logFile.Close()
+	prefixed := &prefixWriter{prefix: "[" + pkg + "] ", w: os.Stdout}
+
+	makepkg := exec.Command("makepkg", args...)
+	makepkg.Dir = dir
+	makepkg.Stdin = os.Stdin
+	makepkg.Stdout = io.MultiWriter(prefixed, logFile)
+	makepkg.Stderr = io.MultiWriter(prefixed, logFile)
+	if err := makepkg.Run(); err != nil {
+		return fmt.Errorf("makepkg failed for %s (see %s): %w", pkg, logFile.Name(), err)
+	}
+	if err := vcs.RecordBuild(info.Pkgbase, info.Source); err != nil {
+		fmt.Printf("[WARN] Failed to record VCS state for %s: %v\n", pkg, err)
+	}
+	return nil
 }
 
 // inspectPKGBUILD fetches and inspects PKGBUILD for risky commands
@@ -120,33 +210,172 @@ func inspectPKGBUILD(pkg string) {
 	}
 }
 
-func InstallPackages(pkgs []string, opts InstallOptions) {
-	seen := make(map[string]bool)
-	order, err := resolveDependencies(pkgs, seen)
-	if err != nil {
-		fmt.Println("Dependency resolution failed:", err)
-		return
+// promptYesNo prints prompt, reads a line from stdin, and reports
+// whether the reply was affirmative. defaultYes controls what an empty
+// reply (just Enter) means.
+func promptYesNo(prompt string, defaultYes bool) bool {
+	fmt.Print(prompt)
+	reader := bufio.NewReader(os.Stdin)
+	reply, _ := reader.ReadString('\n')
+	reply = strings.ToLower(strings.TrimSpace(reply))
+	if reply == "" {
+		return defaultYes
 	}
-	var wg sync.WaitGroup
+	return reply == "y" || reply == "yes"
+}
+
+// buildFailure records why a package never made it into the build queue's
+// success set, either because buildPackage itself failed or because one of
+// its AUR deps did.
+type buildFailure struct {
+	pkg string
+	err error
+}
+
+// runBuildQueue builds plan.Order with up to opts.Parallel workers,
+// starting each package only once every dependency named in plan.Deps has
+// finished. A package whose dependency failed is skipped rather than
+// attempted, but every other independent package still runs to completion:
+// one broken package in a 40-package overnight upgrade shouldn't take the
+// rest down with it. Failures (build failures and skips alike) are
+// returned for the caller to summarize once the whole queue has drained.
+//
+// The one case that does stop the whole queue is the user hitting
+// [A]bort at a PKGBUILD review prompt (reviewPKGBUILD's errBuildAborted):
+// unlike [S]kip, which only gives up on that one package, Abort is the
+// user asking ghostbrew to stop outright, so every package that hasn't
+// started building yet is marked skipped without being attempted.
+func runBuildQueue(plan *dep.Plan, opts InstallOptions) []buildFailure {
+	done := make(map[string]chan struct{}, len(plan.Order))
+	for _, pkg := range plan.Order {
+		done[pkg] = make(chan struct{})
+	}
+
+	var mu sync.Mutex
+	var failed []buildFailure
+	var aborted bool
 	sem := make(chan struct{}, opts.Parallel)
-	fmt.Printf("[INFO] Install order: %v\n", order)
-	for _, pkg := range order {
+
+	var wg sync.WaitGroup
+	for _, pkg := range plan.Order {
 		wg.Add(1)
 		go func(pkg string) {
 			defer wg.Done()
+			defer close(done[pkg])
+
+			var failedDep string
+			for _, d := range plan.Deps[pkg] {
+				<-done[d]
+				mu.Lock()
+				for _, f := range failed {
+					if f.pkg == d {
+						failedDep = d
+					}
+				}
+				mu.Unlock()
+				if failedDep != "" {
+					break
+				}
+			}
+			if failedDep != "" {
+				mu.Lock()
+				failed = append(failed, buildFailure{pkg: pkg, err: fmt.Errorf("skipped: dependency %s failed", failedDep)})
+				mu.Unlock()
+				return
+			}
+
 			sem <- struct{}{}
 			defer func() { <-sem }()
-			checkGPGKey(pkg)
+
+			mu.Lock()
+			wasAborted := aborted
+			mu.Unlock()
+			if wasAborted {
+				mu.Lock()
+				failed = append(failed, buildFailure{pkg: pkg, err: fmt.Errorf("skipped: build queue aborted")})
+				mu.Unlock()
+				return
+			}
+
 			inspectPKGBUILD(pkg)
 			fmt.Printf("[SECURE] Building and installing %s...\n", pkg)
-			cmd := exec.Command("echo", "Simulating build/install for "+pkg)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			if err := cmd.Run(); err != nil {
+			if err := buildPackage(pkg, true, opts); err != nil {
 				fmt.Printf("[ERROR] Build/install failed for %s: %v\n", pkg, err)
+				mu.Lock()
+				failed = append(failed, buildFailure{pkg: pkg, err: err})
+				if errors.Is(err, errBuildAborted) {
+					aborted = true
+				}
+				mu.Unlock()
 			}
 		}(pkg)
 	}
 	wg.Wait()
-	fmt.Println("[INFO] All packages processed.")
+	return failed
+}
+
+func InstallPackages(pkgs []string, opts InstallOptions) {
+	plan, err := dep.Resolve(pkgs)
+	if err != nil {
+		fmt.Println("Dependency resolution failed:", err)
+		return
+	}
+
+	fmt.Println("[INFO] Dependency plan:")
+	if len(plan.RepoDeps) > 0 {
+		fmt.Printf("  repo: %v\n", plan.RepoDeps)
+	}
+	if len(plan.Order) > 0 {
+		fmt.Printf("  aur:  %v\n", plan.Order)
+	}
+	if len(plan.RepoDeps) == 0 && len(plan.Order) == 0 {
+		fmt.Println("[INFO] Nothing to do.")
+		return
+	}
+	if !opts.NoConfirm && !promptYesNo("Proceed with installation? [Y/n] ", true) {
+		return
+	}
+
+	if len(plan.RepoDeps) > 0 {
+		pacmanArgs := append([]string{"-S", "--asdeps", "--needed"}, plan.RepoDeps...)
+		if opts.NoConfirm {
+			pacmanArgs = append(pacmanArgs, "--noconfirm")
+		}
+		install := exec.Command("sudo", append([]string{"pacman"}, pacmanArgs...)...)
+		install.Stdout, install.Stderr, install.Stdin = os.Stdout, os.Stderr, os.Stdin
+		if err := install.Run(); err != nil {
+			fmt.Println("[ERROR] Failed to install repo dependencies:", err)
+		}
+	}
+
+	failed := runBuildQueue(plan, opts)
+	if len(failed) > 0 {
+		fmt.Println("[INFO] Build queue finished with failures:")
+		for _, f := range failed {
+			fmt.Printf("  %s: %v\n", f.pkg, f.err)
+		}
+	} else {
+		fmt.Println("[INFO] All packages processed.")
+	}
+
+	if len(plan.MakeDeps) == 0 {
+		return
+	}
+	var makeOnly []string
+	for name := range plan.MakeDeps {
+		makeOnly = append(makeOnly, name)
+	}
+	remove := opts.RmDeps
+	if !remove && !opts.NoConfirm {
+		remove = promptYesNo(fmt.Sprintf("Remove make dependencies no longer needed? %v [y/N] ", makeOnly), false)
+	}
+	if !remove {
+		return
+	}
+	fmt.Printf("[INFO] Removing make/check dependencies: %v\n", makeOnly)
+	for _, name := range makeOnly {
+		if err := RemovePackage(name, true, true); err != nil {
+			fmt.Printf("[WARN] Failed to remove make dependency %s: %v\n", name, err)
+		}
+	}
 }
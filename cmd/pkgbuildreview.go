@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// emptyTreeSHA is git's well-known empty-tree object, used as the base
+// of the diff on a package's very first build so the whole PKGBUILD is
+// shown rather than nothing.
+const emptyTreeSHA = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// errBuildSkipped and errBuildAborted are sentinel errors returned by
+// reviewPKGBUILD so callers can tell "user declined this one build"
+// apart from a real failure.
+var (
+	errBuildSkipped = fmt.Errorf("skipped by user")
+	errBuildAborted = fmt.Errorf("aborted by user")
+)
+
+// reviewPKGBUILD diffs the checked-out PKGBUILD/.install/.SRCINFO
+// against what's about to be built (origin/HEAD on a re-build, or the
+// empty tree on a first build) and, if anything changed, pages the
+// diff and asks the user how to proceed. skipPrompt bypasses the menu
+// entirely, for trusted setups (Config.DiffMenu == false) or
+// --noconfirm. autoEdit opens the PKGBUILD in $VISUAL/$EDITOR up front
+// (--editmenu) before handing control to the usual v/e/s/a prompt.
+func reviewPKGBUILD(dir string, firstBuild, skipPrompt, autoEdit bool) error {
+	base := "HEAD..origin/HEAD"
+	if firstBuild {
+		base = emptyTreeSHA + "..HEAD"
+	}
+	statArgs := append([]string{"-C", dir, "diff", "--stat", base, "--"}, reviewedPaths...)
+	stat, err := exec.Command("git", statArgs...).Output()
+	if err != nil {
+		return fmt.Errorf("git diff --stat: %w", err)
+	}
+	if len(strings.TrimSpace(string(stat))) == 0 {
+		return nil
+	}
+	if skipPrompt {
+		return nil
+	}
+
+	fmt.Println(string(stat))
+	if autoEdit {
+		if err := openInEditor(filepath.Join(dir, "PKGBUILD")); err != nil {
+			fmt.Println("Failed to open editor:", err)
+		}
+	}
+	for {
+		fmt.Print("[V]iew full diff / [E]dit / [S]kip / [A]bort: ")
+		reader := bufio.NewReader(os.Stdin)
+		line, _ := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "v":
+			diffArgs := append([]string{"-C", dir, "diff", base, "--"}, reviewedPaths...)
+			if err := pageOutput(exec.Command("git", diffArgs...)); err != nil {
+				fmt.Println("Failed to show diff:", err)
+			}
+		case "e":
+			if err := openInEditor(filepath.Join(dir, "PKGBUILD")); err != nil {
+				fmt.Println("Failed to open editor:", err)
+			}
+		case "s", "":
+			return errBuildSkipped
+		case "a":
+			return errBuildAborted
+		default:
+			fmt.Println("Please answer v, e, s, or a.")
+		}
+	}
+}
+
+// reviewedPaths are the files a PKGBUILD diff review pages; *.install
+// covers install hooks without needing to know the package name.
+var reviewedPaths = []string{"PKGBUILD", "*.install", ".SRCINFO"}
+
+// pageOutput runs cmd and streams its stdout through $PAGER (falling
+// back to `less -R`).
+func pageOutput(cmd *exec.Cmd) error {
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less -R"
+	}
+	parts := strings.Fields(pager)
+	pagerCmd := exec.Command(parts[0], parts[1:]...)
+	out, err := cmd.Output()
+	if err != nil {
+		return err
+	}
+	pagerCmd.Stdin = strings.NewReader(string(out))
+	pagerCmd.Stdout = os.Stdout
+	pagerCmd.Stderr = os.Stderr
+	return pagerCmd.Run()
+}
+
+// openInEditor opens path in $VISUAL, falling back to $EDITOR, falling
+// back to prompting the user to edit it manually and press enter.
+func openInEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		fmt.Printf("No $VISUAL or $EDITOR set. Edit %s manually, then press enter to continue.\n", path)
+		bufio.NewReader(os.Stdin).ReadString('\n')
+		return nil
+	}
+	cmd := exec.Command(editor, path)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
@@ -3,10 +3,28 @@ package cmd
 import (
 	"fmt"
 	"os/exec"
+
+	"github.com/GhostKellz/ghostbrew/alpm"
 )
 
 // RemovePackage removes a package, optionally cascading and removing unneeded dependencies
 func RemovePackage(pkg string, cascade, unneeded bool) error {
+	installed, err := alpm.LocalPackages()
+	if err != nil {
+		fmt.Printf("Warning: could not query local package database: %v\n", err)
+	} else {
+		found := false
+		for _, p := range installed {
+			if p.Name == pkg {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("'%s' is not installed", pkg)
+		}
+	}
+
 	args := []string{"-R"}
 	if cascade {
 		args = append(args, "s")
@@ -18,9 +36,9 @@ func RemovePackage(pkg string, cascade, unneeded bool) error {
 	cmd := exec.Command("sudo", append([]string{"pacman"}, args...)...)
 	cmd.Stdout = nil
 	cmd.Stderr = nil
-	err := cmd.Run()
-	if err != nil {
+	if err := cmd.Run(); err != nil {
 		fmt.Printf("Error removing '%s': %v\n", pkg, err)
+		return err
 	}
-	return err
+	return nil
 }
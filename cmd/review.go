@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// reviewCmd runs the PKGBUILD diff review against every pending AUR
+// upgrade without building or installing anything, so users can audit
+// what's about to change ahead of time.
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review pending AUR upgrades' PKGBUILD changes without installing",
+	Run: func(cmd *cobra.Command, args []string) {
+		upgrades := computeAURUpgrades(false)
+		if len(upgrades) == 0 {
+			fmt.Println("No pending AUR upgrades to review.")
+			return
+		}
+		for _, u := range upgrades {
+			fmt.Printf("==> %s (%s -> %s)\n", u.Name, u.OldVer, u.NewVer)
+			if _, _, err := fetchSRCINFO(u.Name, InstallOptions{}); err != nil {
+				fmt.Printf("    %v\n", err)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+}
@@ -8,6 +8,8 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/GhostKellz/ghostbrew/pgp"
 )
 
 var pkgToInstall string
@@ -61,6 +63,10 @@ func Execute() {
 }
 
 func init() {
+	if cfg, err := LoadConfig(); err == nil && len(cfg.KeyServers) > 0 {
+		pgp.KeyServers = cfg.KeyServers
+	}
+
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
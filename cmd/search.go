@@ -4,53 +4,167 @@ Copyright © 2025 Christopher Kelley <ckelley@ghostkellz.sh>
 package cmd
 
 import (
+	"bufio"
 	"fmt"
-	"github.com/manifoldco/promptui"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+
+	"github.com/GhostKellz/ghostbrew/internal/aur"
 )
 
+// searchResult is one line of the unified search listing, whichever
+// source it came from.
+type searchResult struct {
+	Source     string // "extra", "chaotic-aur", "aur", ...
+	Name       string
+	Version    string
+	Desc       string
+	Votes      int
+	Popularity float64
+	OutOfDate  bool
+}
+
+// searchPacman runs `pacman -Ss <term>` and parses its two-line-per-hit
+// output ("repo/name version [installed]" then an indented description
+// line) into searchResults, tagging each by its repo name so
+// Chaotic-AUR entries come back labelled separately from official ones.
+func searchPacman(term string) []searchResult {
+	out, err := exec.Command("pacman", "-Ss", term).Output()
+	if err != nil {
+		return nil
+	}
+	var results []searchResult
+	lines := strings.Split(string(out), "\n")
+	for i := 0; i < len(lines); i++ {
+		header := lines[i]
+		if header == "" || header[0] == ' ' {
+			continue
+		}
+		fields := strings.Fields(header)
+		if len(fields) < 2 {
+			continue
+		}
+		repoName := strings.SplitN(fields[0], "/", 2)
+		if len(repoName) != 2 {
+			continue
+		}
+		desc := ""
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], " ") {
+			desc = strings.TrimSpace(lines[i+1])
+			i++
+		}
+		results = append(results, searchResult{
+			Source:  repoName[0],
+			Name:    repoName[1],
+			Version: fields[1],
+			Desc:    desc,
+		})
+	}
+	return results
+}
+
+// searchAUR queries the AUR RPC search endpoint, with `by` mapped onto
+// the RPC's `by=` parameter (name, name-desc, maintainer, ...).
+func searchAUR(term, by string) []searchResult {
+	pkgs, err := aur.Search(term, by)
+	if err != nil {
+		return nil
+	}
+	results := make([]searchResult, 0, len(pkgs))
+	for _, p := range pkgs {
+		results = append(results, searchResult{
+			Source:     "aur",
+			Name:       p.Name,
+			Version:    p.Version,
+			Desc:       p.Description,
+			Votes:      p.NumVotes,
+			Popularity: p.Popularity,
+			OutOfDate:  p.IsOutOfDate(),
+		})
+	}
+	return results
+}
+
 // searchCmd represents the search command
 var searchCmd = &cobra.Command{
-	Use:   "search",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+	Use:               "search <term>",
+	Short:             "Search official repos, Chaotic-AUR, and the AUR in one unified list",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completePackageNames,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) == 0 {
-			fmt.Println("Please provide a search term.")
+		term := strings.Join(args, " ")
+		by, _ := cmd.Flags().GetString("by")
+		bottomUp, _ := cmd.Flags().GetBool("bottomup")
+
+		var repoResults, aurResults []searchResult
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() { defer wg.Done(); repoResults = searchPacman(term) }()
+		go func() { defer wg.Done(); aurResults = searchAUR(term, by) }()
+		wg.Wait()
+
+		if len(repoResults) == 0 && len(aurResults) == 0 {
+			fmt.Println("No results found.")
 			return
 		}
-		searchTerm := args[0]
-		// Simulate search results (replace with real AUR search logic)
-		results := []string{"hyprland-git", "hyprland-bin", "hyprland-extras"}
-		prompt := promptui.Select{
-			Label: "Select package to install",
-			Items: results,
+
+		var ordered []searchResult
+		if bottomUp {
+			ordered = append(append(ordered, repoResults...), aurResults...)
+		} else {
+			ordered = append(append(ordered, aurResults...), repoResults...)
+		}
+
+		for i, r := range ordered {
+			tag := "[" + r.Source + "]"
+			extra := ""
+			if r.Source == "aur" {
+				extra = fmt.Sprintf(" (votes: %d, pop: %.2f)", r.Votes, r.Popularity)
+				if r.OutOfDate {
+					extra += " [out of date]"
+				}
+			}
+			color.Cyan("%3d  %s %s %s%s", i+1, r.Name, r.Version, tag, extra)
+			if r.Desc != "" {
+				fmt.Printf("     %s\n", r.Desc)
+			}
 		}
-		_, result, err := prompt.Run()
+
+		fmt.Print("\nPackages to install (eg: 1 2 3, 1-3): ")
+		reader := bufio.NewReader(os.Stdin)
+		line, _ := reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return
+		}
+		sel, err := parseIndexRanges(line)
 		if err != nil {
-			fmt.Printf("Prompt failed %v\n", err)
+			fmt.Println("Invalid selection:", err)
 			return
 		}
-		fmt.Printf("You selected %q. Installing...\n", result)
-		// Call parallel installer here (to be implemented)
+
+		var toInstall []string
+		for i, r := range ordered {
+			if sel.marked[i+1] {
+				toInstall = append(toInstall, r.Name)
+			}
+		}
+		if len(toInstall) == 0 {
+			fmt.Println("Nothing selected.")
+			return
+		}
+		fmt.Printf("Installing: %v\n", toInstall)
+		InstallPackages(toInstall, InstallOptions{Parallel: 2})
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(searchCmd)
-
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// searchCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// searchCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	searchCmd.Flags().Bool("bottomup", false, "Show AUR results below repo results (default: AUR results on top)")
+	searchCmd.Flags().String("by", "name-desc", "AUR search field: name, name-desc, or maintainer")
 }
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// selection is the parsed result of a pacman-style numeric prompt reply
+// (e.g. "Packages to exclude (eg: 1 2 3, 1-3):"). marked holds every
+// index the user typed; invert flips the meaning from "exclude these"
+// to "keep only these", matching the `^N`/`^N-M` convention.
+type selection struct {
+	marked map[int]bool
+	invert bool
+}
+
+// parseIndexRanges parses a reply like "1 3 5-7" or "^2-4" into a
+// selection. Entries may be separated by spaces and/or commas.
+func parseIndexRanges(input string) (selection, error) {
+	sel := selection{marked: make(map[int]bool)}
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '\t'
+	})
+	for _, f := range fields {
+		if f == "" {
+			continue
+		}
+		if strings.HasPrefix(f, "^") {
+			sel.invert = true
+			f = strings.TrimPrefix(f, "^")
+		}
+		if dash := strings.Index(f, "-"); dash > 0 {
+			lo, err := strconv.Atoi(f[:dash])
+			if err != nil {
+				return sel, fmt.Errorf("invalid range %q", f)
+			}
+			hi, err := strconv.Atoi(f[dash+1:])
+			if err != nil {
+				return sel, fmt.Errorf("invalid range %q", f)
+			}
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			for i := lo; i <= hi; i++ {
+				sel.marked[i] = true
+			}
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return sel, fmt.Errorf("invalid index %q", f)
+		}
+		sel.marked[n] = true
+	}
+	return sel, nil
+}
+
+// keep reports whether the 1-indexed entry n survives the selection:
+// normally true unless marked (exclude), or only-if-marked when invert
+// is set (keep only these).
+func (s selection) keep(n int) bool {
+	if s.invert {
+		return s.marked[n]
+	}
+	return !s.marked[n]
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import "testing"
+
+func TestParseIndexRanges(t *testing.T) {
+	cases := []struct {
+		input  string
+		marked []int
+		invert bool
+	}{
+		{"1 3 5", []int{1, 3, 5}, false},
+		{"1,3,5", []int{1, 3, 5}, false},
+		{"1-3", []int{1, 2, 3}, false},
+		{"3-1", []int{1, 2, 3}, false}, // reversed range still works
+		{"^2-4", []int{2, 3, 4}, true},
+		{"1 3-5 7", []int{1, 3, 4, 5, 7}, false},
+	}
+	for _, c := range cases {
+		sel, err := parseIndexRanges(c.input)
+		if err != nil {
+			t.Errorf("parseIndexRanges(%q): %v", c.input, err)
+			continue
+		}
+		if sel.invert != c.invert {
+			t.Errorf("parseIndexRanges(%q).invert = %v, want %v", c.input, sel.invert, c.invert)
+		}
+		for _, n := range c.marked {
+			if !sel.marked[n] {
+				t.Errorf("parseIndexRanges(%q): %d not marked", c.input, n)
+			}
+		}
+		if len(sel.marked) != len(c.marked) {
+			t.Errorf("parseIndexRanges(%q): marked = %v, want only %v", c.input, sel.marked, c.marked)
+		}
+	}
+}
+
+func TestParseIndexRangesInvalid(t *testing.T) {
+	for _, input := range []string{"abc", "1-abc", "abc-3"} {
+		if _, err := parseIndexRanges(input); err == nil {
+			t.Errorf("parseIndexRanges(%q) did not error", input)
+		}
+	}
+}
+
+func TestSelectionKeep(t *testing.T) {
+	sel := selection{marked: map[int]bool{2: true}}
+	if sel.keep(2) {
+		t.Error("keep(2) = true, want false: 2 is marked for exclusion")
+	}
+	if !sel.keep(1) {
+		t.Error("keep(1) = false, want true: 1 is not marked")
+	}
+
+	inverted := selection{marked: map[int]bool{2: true}, invert: true}
+	if !inverted.keep(2) {
+		t.Error("inverted keep(2) = false, want true: 2 is marked to keep")
+	}
+	if inverted.keep(1) {
+		t.Error("inverted keep(1) = true, want false: 1 is not marked to keep")
+	}
+}
@@ -1,40 +1,248 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/GhostKellz/ghostbrew/alpm"
+	"github.com/GhostKellz/ghostbrew/devel"
+	"github.com/GhostKellz/ghostbrew/internal/aur"
+	"github.com/GhostKellz/ghostbrew/news"
+	"github.com/GhostKellz/ghostbrew/vcs"
 )
 
+// aurUpgrade is one pending AUR/foreign package upgrade, numbered
+// alongside repoUpgrades in the combined menu.
+type aurUpgrade struct {
+	Name   string
+	OldVer string
+	NewVer string
+}
+
 var upgradeCmd = &cobra.Command{
 	Use:   "upgrade",
 	Short: "Sync and upgrade all packages (official, Chaotic-AUR, AUR)",
 	Run: func(cmd *cobra.Command, args []string) {
-		// Parse flags
+		newsOnly, _ := cmd.Flags().GetBool("news")
+		if newsOnly {
+			showNews()
+			return
+		}
+
 		noConfirm, _ := cmd.Flags().GetBool("no-confirm")
 		aurOnly, _ := cmd.Flags().GetBool("aur-only")
-		// Pacman system upgrade
+		checkDevel, _ := cmd.Flags().GetBool("devel")
+		combined, _ := cmd.Flags().GetBool("combinedupgrade")
+		noCombined, _ := cmd.Flags().GetBool("nocombinedupgrade")
+		if noCombined {
+			combined = false
+		}
+
+		cfg, _ := LoadConfig()
+		if cfg.newsOnUpgradeEnabled() {
+			showNews()
+			if !noConfirm {
+				fmt.Print("Proceed with installation? [Y/n] ")
+				reader := bufio.NewReader(os.Stdin)
+				reply, _ := reader.ReadString('\n')
+				reply = strings.ToLower(strings.TrimSpace(reply))
+				if reply == "n" || reply == "no" {
+					return
+				}
+			}
+		}
+		defer news.RecordUpgrade()
+
+		if !combined {
+			runSplitUpgrade(noConfirm, aurOnly, checkDevel)
+			return
+		}
+
 		if !aurOnly {
-			fmt.Println("Upgrading system packages with pacman...")
-			pacmanArgs := []string{"-Syu"}
+			fmt.Println("Syncing package databases...")
+			sync := exec.Command("sudo", "pacman", "-Sy")
+			sync.Stdout, sync.Stderr = os.Stdout, os.Stderr
+			_ = sync.Run()
+		}
+
+		var repoUpgrades []alpm.Upgrade
+		if !aurOnly {
+			var err error
+			repoUpgrades, err = alpm.UpgradablePackages()
+			if err != nil {
+				fmt.Println("Failed to query sync database:", err)
+				return
+			}
+		}
+		aurUpgrades := computeAURUpgrades(checkDevel)
+
+		if len(repoUpgrades) == 0 && len(aurUpgrades) == 0 {
+			fmt.Println("Nothing to do.")
+			return
+		}
+
+		// Number AUR packages first, then repo packages, so the
+		// highest-churn set sits closest to the prompt.
+		idx := 1
+		fmt.Println()
+		for _, u := range aurUpgrades {
+			fmt.Printf("%3d  %-30s %s -> %s  [aur]\n", idx, u.Name, u.OldVer, u.NewVer)
+			idx++
+		}
+		for _, u := range repoUpgrades {
+			fmt.Printf("%3d  %-30s %s -> %s  [%s]\n", idx, u.Name, u.OldVer, u.NewVer, u.Repo)
+			idx++
+		}
+		fmt.Println()
+
+		sel := selection{marked: map[int]bool{}}
+		if !noConfirm {
+			fmt.Print("Packages to exclude (eg: 1 2 3, 1-3): ")
+			reader := bufio.NewReader(os.Stdin)
+			line, _ := reader.ReadString('\n')
+			parsed, err := parseIndexRanges(strings.TrimSpace(line))
+			if err != nil {
+				fmt.Println("Invalid selection:", err)
+				return
+			}
+			sel = parsed
+		}
+
+		var aurTargets []string
+		var repoTargets []string
+		n := 1
+		for _, u := range aurUpgrades {
+			if sel.keep(n) {
+				aurTargets = append(aurTargets, u.Name)
+			}
+			n++
+		}
+		for _, u := range repoUpgrades {
+			if sel.keep(n) {
+				repoTargets = append(repoTargets, u.Name)
+			}
+			n++
+		}
+
+		if len(repoTargets) > 0 {
+			pacmanArgs := append([]string{"-S", "--needed"}, repoTargets...)
 			if noConfirm {
 				pacmanArgs = append(pacmanArgs, "--noconfirm")
 			}
-			cmdPacman := exec.Command("sudo", append([]string{"pacman"}, pacmanArgs...)...)
-			cmdPacman.Stdout = os.Stdout
-			cmdPacman.Stderr = os.Stderr
-			_ = cmdPacman.Run()
+			install := exec.Command("sudo", append([]string{"pacman"}, pacmanArgs...)...)
+			install.Stdout, install.Stderr, install.Stdin = os.Stdout, os.Stderr, os.Stdin
+			_ = install.Run()
+		}
+		if len(aurTargets) > 0 {
+			InstallPackages(aurTargets, InstallOptions{Parallel: 2, NoConfirm: noConfirm})
 		}
-		// TODO: Query installed AUR packages and upgrade them
-		fmt.Println("[TODO] AUR upgrade logic not yet implemented.")
 	},
 }
 
+// showNews prints Arch news entries published since the last recorded
+// upgrade, so breaking-change announcements surface before -Syu runs.
+func showNews() {
+	entries, err := news.Fetch()
+	if err != nil {
+		fmt.Println("Failed to fetch Arch news:", err)
+		return
+	}
+	since, _ := news.LastUpgrade()
+	shown := 0
+	for _, e := range entries {
+		if e.Published.Before(since) || e.Published.Equal(since) {
+			continue
+		}
+		fmt.Printf("==> %s (%s)\n%s\n\n", e.Title, e.Published.Format("2006-01-02"), news.Wrap(e.Content, 80))
+		shown++
+	}
+	if shown == 0 {
+		fmt.Println("No new Arch news since your last upgrade.")
+	}
+}
+
+// computeAURUpgrades returns every foreign package with a newer AUR
+// version, plus (when checkDevel is set) any -git/-svn/-hg/-bzr
+// package whose tracked upstream HEAD has moved even if pkgver hasn't.
+func computeAURUpgrades(checkDevel bool) []aurUpgrade {
+	foreign, err := alpm.ForeignPackages()
+	if err != nil {
+		fmt.Println("Failed to query local package database:", err)
+		return nil
+	}
+	names := make([]string, len(foreign))
+	for i, pkg := range foreign {
+		names[i] = pkg.Name
+	}
+	infos, err := aur.Info(names...)
+	if err != nil {
+		fmt.Println("Failed to fetch AUR info:", err)
+		return nil
+	}
+
+	var upgrades []aurUpgrade
+	for _, pkg := range foreign {
+		info, ok := infos[pkg.Name]
+		if !ok || info.Version == "" {
+			continue
+		}
+		if alpm.VerCmp(pkg.Version, info.Version) < 0 {
+			upgrades = append(upgrades, aurUpgrade{Name: pkg.Name, OldVer: pkg.Version, NewVer: info.Version})
+			continue
+		}
+		if checkDevel && vcs.IsDevel(pkg.Name) {
+			if changed, err := devel.CheckUpgrade(pkg.Name); err == nil && changed {
+				upgrades = append(upgrades, aurUpgrade{Name: pkg.Name, OldVer: pkg.Version, NewVer: pkg.Version + " (devel)"})
+			}
+		}
+	}
+	return upgrades
+}
+
+// runSplitUpgrade is the pre-combined-menu behavior: pacman -Syu runs to
+// completion on its own, then AUR packages are resolved and installed
+// in a separate, non-atomic pass.
+func runSplitUpgrade(noConfirm, aurOnly, checkDevel bool) {
+	if !aurOnly {
+		fmt.Println("Upgrading system packages with pacman...")
+		pacmanArgs := []string{"-Syu"}
+		if noConfirm {
+			pacmanArgs = append(pacmanArgs, "--noconfirm")
+		}
+		cmdPacman := exec.Command("sudo", append([]string{"pacman"}, pacmanArgs...)...)
+		cmdPacman.Stdout = os.Stdout
+		cmdPacman.Stderr = os.Stderr
+		_ = cmdPacman.Run()
+	}
+
+	fmt.Println("Checking for AUR package upgrades...")
+	upgrades := computeAURUpgrades(checkDevel)
+	if upgrades == nil {
+		return
+	}
+	if len(upgrades) == 0 {
+		fmt.Println("All AUR packages are up to date.")
+		return
+	}
+	var toUpdate []string
+	for _, u := range upgrades {
+		toUpdate = append(toUpdate, u.Name)
+	}
+	fmt.Printf("AUR packages to upgrade: %v\n", toUpdate)
+	InstallPackages(toUpdate, InstallOptions{Parallel: 2, NoConfirm: noConfirm})
+}
+
 func init() {
 	rootCmd.AddCommand(upgradeCmd)
 	upgradeCmd.Flags().Bool("no-confirm", false, "Do not prompt for confirmation")
 	upgradeCmd.Flags().Bool("aur-only", false, "Only upgrade AUR packages")
+	upgradeCmd.Flags().Bool("devel", true, "Check -git/-svn/-hg/-bzr packages for upstream changes; disable with --devel=false")
+	upgradeCmd.Flags().Bool("combinedupgrade", true, "Sync and upgrade repo+AUR packages in a single combined pass")
+	upgradeCmd.Flags().Bool("nocombinedupgrade", false, "Perform the sync refresh and the upgrade as separate, non-atomic steps")
+	upgradeCmd.Flags().BoolP("news", "w", false, "Show Arch Linux news since the last upgrade, without upgrading")
 }
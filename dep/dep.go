@@ -0,0 +1,233 @@
+// Package dep resolves AUR/repo dependency graphs into a topologically
+// sorted build plan. It replaces the old goroutine-per-dependency walk
+// in cmd/installer.go, which had no cycle detection and ignored
+// versioned constraints (`foo>=1.2`) and `provides=` satisfaction.
+package dep
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/GhostKellz/ghostbrew/alpm"
+	"github.com/GhostKellz/ghostbrew/internal/aur"
+)
+
+// constraintRe strips a versioned dependency constraint like ">=1.2" or
+// "=3.0-1" off a dep string, leaving just the package/provides name.
+var constraintRe = regexp.MustCompile(`[<>=].*$`)
+
+func stripConstraint(dep string) string {
+	return constraintRe.ReplaceAllString(dep, "")
+}
+
+// Plan is a resolved, dependency-ordered build plan.
+type Plan struct {
+	Order    []string            // AUR pkgbases in build order (deps before dependents)
+	Deps     map[string][]string // pkgbase -> its direct AUR build deps (subset of Order), for a dependency-aware build queue
+	RepoDeps []string            // repo/Chaotic-AUR deps not yet installed, for a single pacman -S --asdeps
+	MakeDeps map[string]bool     // entries in Order pulled in purely as make/check deps
+}
+
+// node holds one AUR pkgbase's direct dependency edges, as discovered by
+// discover. Ordering (deps before buildDeps, and the order within each)
+// mirrors the AUR RPC's Depends/MakeDepends/CheckDepends arrays, so the
+// later topological sort produces the same result as walking the graph
+// directly would.
+type node struct {
+	deps      []string // depends, by stripped name
+	buildDeps []string // makedepends+checkdepends, by stripped name
+}
+
+type resolver struct {
+	cache    map[string]aur.Package // AUR info already fetched, keyed by name
+	resolved map[string]bool        // name -> satisfied outside the AUR (repo, provides, or unresolvable)
+	nodes    map[string]node        // AUR pkgbase -> its direct dep edges, from discover
+	repoDeps []string
+	makeDeps map[string]bool
+
+	color   map[string]int // 0=unvisited, 1=visiting, 2=visited, for the topological sort
+	order   []string
+	inOrder map[string]bool
+	deps    map[string][]string
+}
+
+const (
+	white = 0
+	gray  = 1
+	black = 2
+)
+
+// Resolve walks targets' AUR dependency graphs (depends, makedepends,
+// checkdepends), splitting each dependency into already-installed,
+// repo/Chaotic-AUR, or AUR, and returns a topologically sorted build
+// plan. It returns an error if a dependency cycle is detected.
+func Resolve(targets []string) (*Plan, error) {
+	r := &resolver{
+		cache:    make(map[string]aur.Package),
+		resolved: make(map[string]bool),
+		nodes:    make(map[string]node),
+		makeDeps: make(map[string]bool),
+		color:    make(map[string]int),
+		inOrder:  make(map[string]bool),
+		deps:     make(map[string][]string),
+	}
+
+	seeds := make([]string, len(targets))
+	for i, t := range targets {
+		seeds[i] = stripConstraint(t)
+	}
+	if err := r.discover(seeds); err != nil {
+		return nil, err
+	}
+	r.reconcileMakeDeps()
+	for _, name := range seeds {
+		if err := r.visit(name); err != nil {
+			return nil, err
+		}
+	}
+	// Only make deps this plan actually builds are candidates for removal
+	// afterward; anything already installed or provided stays.
+	for name := range r.makeDeps {
+		if !r.inOrder[name] {
+			delete(r.makeDeps, name)
+		}
+	}
+	return &Plan{Order: r.order, Deps: r.deps, RepoDeps: r.repoDeps, MakeDeps: r.makeDeps}, nil
+}
+
+// discover does the network side of resolution: a breadth-first walk of
+// the dependency graph that fetches each frontier level's AUR info in a
+// single batched aur.Info call (the RPC's type=info&arg[]= multi-arg
+// form), rather than one HTTP round-trip per dependency name. Results
+// are recorded into r.nodes/r.resolved/r.repoDeps/r.makeDeps for the
+// purely in-memory topological sort in visit to consume afterward.
+func (r *resolver) discover(seeds []string) error {
+	seen := make(map[string]bool)
+	frontier := seeds
+	for len(frontier) > 0 {
+		var toFetch []string
+		for _, name := range frontier {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			if pkg, ok, err := alpm.FindSync(name); err == nil && ok {
+				r.resolved[name] = true
+				if pkg.Repo != "local" {
+					// Available in a repo/Chaotic-AUR but not installed
+					// yet; pacman needs to install it, but it brings
+					// its own deps.
+					r.repoDeps = append(r.repoDeps, name)
+				}
+				continue
+			}
+			if ok, err := alpm.ProvidesSync(name); err == nil && ok {
+				r.resolved[name] = true
+				continue
+			}
+			toFetch = append(toFetch, name)
+		}
+		if len(toFetch) == 0 {
+			return nil
+		}
+
+		fetched, err := aur.Info(toFetch...)
+		if err != nil {
+			return err
+		}
+		var next []string
+		for _, name := range toFetch {
+			info, ok := fetched[name]
+			if !ok {
+				// Maybe another AUR package provides it under a
+				// different pkgname; fall back to treating it as
+				// resolved-elsewhere rather than failing the plan.
+				r.resolved[name] = true
+				continue
+			}
+			r.cache[name] = info
+
+			var n node
+			for _, d := range info.Depends {
+				dn := stripConstraint(d)
+				n.deps = append(n.deps, dn)
+				next = append(next, dn)
+			}
+			for _, d := range append(append([]string{}, info.MakeDepends...), info.CheckDepends...) {
+				dn := stripConstraint(d)
+				n.buildDeps = append(n.buildDeps, dn)
+				r.makeDeps[dn] = true
+				next = append(next, dn)
+			}
+			r.nodes[name] = n
+		}
+		frontier = next
+	}
+	return nil
+}
+
+// reconcileMakeDeps drops any name from r.makeDeps that's also required at
+// runtime: a real Depends of some other discovered node, or a repo/
+// Chaotic-AUR package pacman needs to install. discover records every name
+// seen under MakeDepends/CheckDepends as it's encountered, with no regard
+// for whether that same name also turns up as a real Depends elsewhere in
+// the graph (e.g. target A depends on X, target B makedepends on X) — so
+// the make-dep set has to be reconciled against the full graph once
+// discover has finished, rather than judged name-by-name as it's built.
+func (r *resolver) reconcileMakeDeps() {
+	for _, n := range r.nodes {
+		for _, d := range n.deps {
+			delete(r.makeDeps, d)
+		}
+	}
+	for _, d := range r.repoDeps {
+		delete(r.makeDeps, d)
+	}
+}
+
+// visit resolves a single name against the graph discover already
+// fetched, recursing into its dependencies before appending it to the
+// plan (post-order => topological). It does no network I/O itself.
+func (r *resolver) visit(name string) error {
+	switch r.color[name] {
+	case black:
+		return nil
+	case gray:
+		return fmt.Errorf("dependency cycle detected at %q", name)
+	}
+
+	if r.resolved[name] {
+		r.color[name] = black
+		return nil
+	}
+	n, ok := r.nodes[name]
+	if !ok {
+		// Not actually reachable (e.g. a target discover never saw);
+		// treat as resolved-elsewhere rather than failing the plan.
+		r.color[name] = black
+		return nil
+	}
+
+	r.color[name] = gray
+	for _, d := range n.deps {
+		if err := r.visit(d); err != nil {
+			return err
+		}
+		if r.inOrder[d] {
+			r.deps[name] = append(r.deps[name], d)
+		}
+	}
+	for _, d := range n.buildDeps {
+		if err := r.visit(d); err != nil {
+			return err
+		}
+		if r.inOrder[d] {
+			r.deps[name] = append(r.deps[name], d)
+		}
+	}
+	r.color[name] = black
+	r.order = append(r.order, name)
+	r.inOrder[name] = true
+	return nil
+}
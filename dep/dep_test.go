@@ -0,0 +1,88 @@
+package dep
+
+import "testing"
+
+func TestStripConstraint(t *testing.T) {
+	cases := map[string]string{
+		"foo":       "foo",
+		"foo>=1.2":  "foo",
+		"foo=3.0-1": "foo",
+		"foo<2":     "foo",
+		"foo-bar":   "foo-bar",
+	}
+	for in, want := range cases {
+		if got := stripConstraint(in); got != want {
+			t.Errorf("stripConstraint(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func newTestResolver() *resolver {
+	return &resolver{
+		resolved: make(map[string]bool),
+		nodes:    make(map[string]node),
+		makeDeps: make(map[string]bool),
+		color:    make(map[string]int),
+		inOrder:  make(map[string]bool),
+		deps:     make(map[string][]string),
+	}
+}
+
+// TestReconcileMakeDepsDropsRuntimeDeps covers the case the request asked
+// for: pkg A depends on X, pkg B makedepends on X -- X must not end up in
+// the final MakeDeps set, since removing it after the build would break A.
+func TestReconcileMakeDepsDropsRuntimeDeps(t *testing.T) {
+	r := newTestResolver()
+	r.nodes["a"] = node{deps: []string{"x"}}
+	r.nodes["b"] = node{buildDeps: []string{"x"}}
+	r.makeDeps["x"] = true
+	r.makeDeps["y"] = true // pure make dep, no other node depends on it
+
+	r.reconcileMakeDeps()
+
+	if r.makeDeps["x"] {
+		t.Error("reconcileMakeDeps left x in makeDeps, but a real Depends requires it")
+	}
+	if !r.makeDeps["y"] {
+		t.Error("reconcileMakeDeps dropped y, which nothing else depends on")
+	}
+}
+
+func TestReconcileMakeDepsDropsRepoDeps(t *testing.T) {
+	r := newTestResolver()
+	r.repoDeps = []string{"x"}
+	r.makeDeps["x"] = true
+
+	r.reconcileMakeDeps()
+
+	if r.makeDeps["x"] {
+		t.Error("reconcileMakeDeps left x in makeDeps, but it's also a RepoDep")
+	}
+}
+
+func TestVisitOrdersDepsBeforeDependents(t *testing.T) {
+	r := newTestResolver()
+	r.nodes["a"] = node{deps: []string{"b"}}
+	r.nodes["b"] = node{}
+
+	if err := r.visit("a"); err != nil {
+		t.Fatalf("visit: %v", err)
+	}
+	want := []string{"b", "a"}
+	if len(r.order) != len(want) || r.order[0] != want[0] || r.order[1] != want[1] {
+		t.Errorf("order = %v, want %v", r.order, want)
+	}
+	if got := r.deps["a"]; len(got) != 1 || got[0] != "b" {
+		t.Errorf("deps[a] = %v, want [b]", got)
+	}
+}
+
+func TestVisitDetectsCycle(t *testing.T) {
+	r := newTestResolver()
+	r.nodes["a"] = node{deps: []string{"b"}}
+	r.nodes["b"] = node{deps: []string{"a"}}
+
+	if err := r.visit("a"); err == nil {
+		t.Error("visit did not return an error for a cyclic graph")
+	}
+}
@@ -0,0 +1,143 @@
+// Package devel checks installed VCS/devel packages (-git, -svn, -hg,
+// -bzr, -darcs) for upstream changes even when the AUR-reported
+// Version hasn't moved, mirroring the behavior yay added in its 2.x
+// refactor. It builds on vcs's source parsing and HEAD resolution but
+// tracks state independently in $XDG_STATE_HOME/ghostbrew/devel.json,
+// since it checks packages that may never have gone through
+// vcs.RecordBuild (e.g. installed by another tool).
+package devel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/GhostKellz/ghostbrew/srcinfo"
+	"github.com/GhostKellz/ghostbrew/vcs"
+)
+
+// State maps pkgbase to the VCS sources (with last-seen commit hash)
+// recorded at its last devel check.
+type State map[string][]vcs.VCSInfo
+
+// statePath returns $XDG_STATE_HOME/ghostbrew/devel.json.
+func statePath() (string, error) {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		stateHome = filepath.Join(os.Getenv("HOME"), ".local", "state")
+	}
+	dir := filepath.Join(stateHome, "ghostbrew")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "devel.json"), nil
+}
+
+// Load reads the persisted devel state, returning an empty State if it
+// doesn't exist yet.
+func Load() (State, error) {
+	path, err := statePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return State{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := State{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("devel: parse %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// Save persists the state to disk.
+func (s State) Save() error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// fetchSources downloads pkgbase's .SRCINFO straight from the AUR git
+// repo (rather than a local checkout, since the package may have been
+// built by another tool) and returns its parsed VCS sources.
+func fetchSources(pkgbase string) ([]vcs.VCSInfo, error) {
+	url := "https://aur.archlinux.org/cgit/aur.git/plain/.SRCINFO?h=" + pkgbase
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("devel: fetch .SRCINFO for %s: %w", pkgbase, err)
+	}
+	defer resp.Body.Close()
+	info, err := srcinfo.Parse(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("devel: parse .SRCINFO for %s: %w", pkgbase, err)
+	}
+	return vcs.ParseSources(info.Source), nil
+}
+
+// CheckUpgrade reports whether pkgbase's upstream VCS source has moved
+// since the last devel check, and persists the newly resolved commit
+// hashes so the next check compares against them. If ghostbrew itself
+// last built pkgbase, vcs.RecordBuild already tracked its source SHAs in
+// vcs.json, so CheckUpgrade defers to vcs.NeedsRebuild instead of
+// re-fetching .SRCINFO from the AUR; the independent devel.json state
+// here only covers packages vcs has never tracked (installed by another
+// tool, or never built through ghostbrew).
+func CheckUpgrade(pkgbase string) (bool, error) {
+	if rebuild, tracked, err := vcs.NeedsRebuild(pkgbase); err == nil && tracked {
+		return rebuild, nil
+	}
+
+	sources, err := fetchSources(pkgbase)
+	if err != nil {
+		return false, err
+	}
+	if len(sources) == 0 {
+		return false, nil
+	}
+
+	state, err := Load()
+	if err != nil {
+		return false, err
+	}
+	previous := state[pkgbase]
+
+	changed := false
+	for i, src := range sources {
+		sha, err := vcs.ResolveHEAD(src)
+		if err != nil {
+			continue
+		}
+		sources[i].SHA = sha
+		if !sameSource(previous, src.URL, sha) {
+			changed = true
+		}
+	}
+
+	state[pkgbase] = sources
+	if err := state.Save(); err != nil {
+		return changed, err
+	}
+	return changed, nil
+}
+
+// sameSource reports whether previous already recorded url at sha.
+func sameSource(previous []vcs.VCSInfo, url, sha string) bool {
+	for _, p := range previous {
+		if p.URL == url {
+			return p.SHA == sha
+		}
+	}
+	return false
+}
@@ -0,0 +1,256 @@
+// Package aur is a typed client for the AUR RPC (v5), used in place of
+// the ad-hoc http.Get/json.Decode calls that used to be duplicated
+// across cmd/info.go, cmd/search.go, cmd/upgrade.go, and dep/dep.go.
+// Info lookups are cached on disk so repeated dependency-graph and
+// upgrade-check lookups for the same package don't re-hit the network.
+package aur
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const rpcURL = "https://aur.archlinux.org/rpc/?"
+
+// infoCacheTTL bounds how long a cached Info result is trusted before
+// a lookup re-fetches it from the RPC.
+const infoCacheTTL = 10 * time.Minute
+
+// Package is one AUR package record, covering every field the existing
+// info/search/upgrade/dep call sites read.
+type Package struct {
+	Name           string
+	Version        string
+	Description    string
+	Maintainer     string
+	URL            string
+	NumVotes       int
+	Popularity     float64
+	OutOfDate      *int64
+	FirstSubmitted int64
+	LastModified   int64
+	Depends        []string
+	MakeDepends    []string
+	CheckDepends   []string
+	Provides       []string
+}
+
+// IsOutOfDate reports whether the AUR maintainer has flagged this
+// package as out-of-date.
+func (p Package) IsOutOfDate() bool {
+	return p.OutOfDate != nil
+}
+
+// infoBatchSize caps how many names go into a single type=info RPC
+// call; the endpoint rejects overly long query strings past a few
+// hundred names.
+const infoBatchSize = 200
+
+type cacheEntry struct {
+	Package   Package   `json:"package"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Client is a typed AUR RPC client with an in-memory and on-disk TTL
+// cache for Info lookups.
+type Client struct {
+	HTTPClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+	dirty bool
+}
+
+// NewClient returns a Client with its on-disk Info cache loaded, if
+// present.
+func NewClient() *Client {
+	c := &Client{HTTPClient: http.DefaultClient, cache: make(map[string]cacheEntry)}
+	c.loadDiskCache()
+	return c
+}
+
+// DefaultClient is the package-level Client used by Info and Search.
+var DefaultClient = NewClient()
+
+// Info batch-fetches AUR info for names, serving fresh entries from
+// cache and only hitting the RPC for misses or expired entries.
+func Info(names ...string) (map[string]Package, error) {
+	return DefaultClient.Info(names)
+}
+
+// Search queries the AUR RPC search endpoint, with by mapped onto the
+// RPC's by= parameter (name, name-desc, maintainer, ...). Search
+// results are not cached: the query space is too large and results
+// change too often for a TTL cache to pay for itself.
+func Search(term, by string) ([]Package, error) {
+	return DefaultClient.Search(term, by)
+}
+
+// Info is the Client method backing the package-level Info func.
+func (c *Client) Info(names []string) (map[string]Package, error) {
+	results := make(map[string]Package, len(names))
+	var misses []string
+
+	c.mu.Lock()
+	now := time.Now()
+	for _, n := range names {
+		entry, ok := c.cache[n]
+		if ok && now.Sub(entry.FetchedAt) < infoCacheTTL {
+			results[n] = entry.Package
+			continue
+		}
+		misses = append(misses, n)
+	}
+	c.mu.Unlock()
+
+	for start := 0; start < len(misses); start += infoBatchSize {
+		end := start + infoBatchSize
+		if end > len(misses) {
+			end = len(misses)
+		}
+		fetched, err := c.fetchInfo(misses[start:end])
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		for name, pkg := range fetched {
+			results[name] = pkg
+			c.cache[name] = cacheEntry{Package: pkg, FetchedAt: now}
+			c.dirty = true
+		}
+		c.mu.Unlock()
+	}
+
+	if c.dirty {
+		c.saveDiskCache()
+	}
+	return results, nil
+}
+
+func (c *Client) fetchInfo(names []string) (map[string]Package, error) {
+	q := url.Values{}
+	q.Set("v", "5")
+	q.Set("type", "info")
+	for _, n := range names {
+		q.Add("arg[]", n)
+	}
+	resp, err := c.httpClient().Get(rpcURL + q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("aur: info: %w", err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Results []Package
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("aur: info: decode: %w", err)
+	}
+	results := make(map[string]Package, len(body.Results))
+	for _, pkg := range body.Results {
+		results[pkg.Name] = pkg
+	}
+	return results, nil
+}
+
+// Search is the Client method backing the package-level Search func.
+func (c *Client) Search(term, by string) ([]Package, error) {
+	q := url.Values{}
+	q.Set("v", "5")
+	q.Set("type", "search")
+	q.Set("arg", term)
+	if by != "" {
+		q.Set("by", by)
+	}
+	resp, err := c.httpClient().Get(rpcURL + q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("aur: search: %w", err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Results []Package
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("aur: search: decode: %w", err)
+	}
+	return body.Results, nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// cachePath returns $XDG_CACHE_HOME/ghostbrew/aur/info-cache.json.gz.
+func cachePath() (string, error) {
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		cacheHome = filepath.Join(os.Getenv("HOME"), ".cache")
+	}
+	dir := filepath.Join(cacheHome, "ghostbrew", "aur")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "info-cache.json.gz"), nil
+}
+
+// loadDiskCache best-effort loads a previously persisted Info cache;
+// any failure just leaves the Client with an empty cache.
+func (c *Client) loadDiskCache() {
+	path, err := cachePath()
+	if err != nil {
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return
+	}
+	defer gz.Close()
+	var entries map[string]cacheEntry
+	if err := json.NewDecoder(gz).Decode(&entries); err != nil {
+		return
+	}
+	c.mu.Lock()
+	c.cache = entries
+	c.mu.Unlock()
+}
+
+// saveDiskCache persists the current Info cache to disk, gzip-compressed.
+func (c *Client) saveDiskCache() {
+	path, err := cachePath()
+	if err != nil {
+		return
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+
+	c.mu.Lock()
+	entries := make(map[string]cacheEntry, len(c.cache))
+	for name, entry := range c.cache {
+		entries[name] = entry
+	}
+	c.dirty = false
+	c.mu.Unlock()
+
+	if err := json.NewEncoder(gz).Encode(entries); err != nil {
+		return
+	}
+}
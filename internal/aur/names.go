@@ -0,0 +1,100 @@
+package aur
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// namesURL is the full AUR package name dump used for shell-completion.
+const namesURL = "https://aur.archlinux.org/packages.gz"
+
+// NamesCacheTTL bounds how long a cached packages.gz is trusted before
+// Names re-downloads it. 48h matches the refresh cadence yay 1.115 uses
+// for its own AUR name completion cache.
+const NamesCacheTTL = 48 * time.Hour
+
+// namesCachePath returns $XDG_CACHE_HOME/ghostbrew/packages.gz.
+func namesCachePath() (string, error) {
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		cacheHome = filepath.Join(os.Getenv("HOME"), ".cache")
+	}
+	dir := filepath.Join(cacheHome, "ghostbrew")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "packages.gz"), nil
+}
+
+// Names returns every package name in the AUR, for shell-completion
+// purposes. The backing packages.gz is cached on disk and only
+// re-fetched once NamesCacheTTL has elapsed; a failed refresh falls back
+// to whatever's already cached rather than leaving completion empty.
+func Names() ([]string, error) {
+	path, err := namesCachePath()
+	if err != nil {
+		return nil, err
+	}
+	info, statErr := os.Stat(path)
+	if statErr != nil || time.Since(info.ModTime()) > NamesCacheTTL {
+		if dlErr := downloadNames(path); dlErr != nil && statErr != nil {
+			return nil, dlErr
+		}
+	}
+	return readNames(path)
+}
+
+// downloadNames fetches namesURL and atomically replaces path with it.
+func downloadNames(path string) error {
+	resp, err := http.Get(namesURL)
+	if err != nil {
+		return fmt.Errorf("aur: names: %w", err)
+	}
+	defer resp.Body.Close()
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("aur: names: %w", err)
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return fmt.Errorf("aur: names: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("aur: names: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// readNames parses the gzipped, one-name-per-line packages.gz format.
+func readNames(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("aur: names: %w", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("aur: names: %w", err)
+	}
+	defer gz.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	return names, scanner.Err()
+}
@@ -0,0 +1,143 @@
+// Package news fetches and renders the Arch Linux news feed so
+// breaking-change announcements (manual interventions, keyring bumps)
+// surface before a system upgrade rather than after it fails.
+package news
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+const feedURL = "https://archlinux.org/feeds/news/"
+
+// Entry is one Arch news item.
+type Entry struct {
+	Title     string
+	Published time.Time
+	Link      string
+	Content   string
+}
+
+// atomFeed mirrors the subset of the Atom schema the news feed uses.
+type atomFeed struct {
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title     string `xml:"title"`
+	Published string `xml:"published"`
+	Link      struct {
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+	Content string `xml:"content"`
+}
+
+// Fetch downloads and parses the Arch news Atom feed.
+func Fetch() ([]Entry, error) {
+	resp, err := http.Get(feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("news: fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var feed atomFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("news: parse: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(feed.Entries))
+	for _, e := range feed.Entries {
+		published, _ := time.Parse(time.RFC3339, e.Published)
+		entries = append(entries, Entry{
+			Title:     strings.TrimSpace(e.Title),
+			Published: published,
+			Link:      e.Link.Href,
+			Content:   stripHTML(e.Content),
+		})
+	}
+	return entries, nil
+}
+
+// stripHTML removes tags from an HTML fragment, keeping only the text
+// content, using a minimal golang.org/x/net/html tokenizer pass.
+func stripHTML(s string) string {
+	var sb strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.TrimSpace(sb.String())
+		case html.TextToken:
+			sb.Write(tokenizer.Text())
+			sb.WriteByte(' ')
+		}
+	}
+}
+
+// Wrap wraps s to width columns on word boundaries.
+func Wrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	var out strings.Builder
+	lineLen := 0
+	for _, word := range strings.Fields(s) {
+		if lineLen > 0 && lineLen+1+len(word) > width {
+			out.WriteByte('\n')
+			lineLen = 0
+		} else if lineLen > 0 {
+			out.WriteByte(' ')
+			lineLen++
+		}
+		out.WriteString(word)
+		lineLen += len(word)
+	}
+	return out.String()
+}
+
+// statePath returns $XDG_STATE_HOME/ghostbrew/last-upgrade.
+func statePath() (string, error) {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		stateHome = filepath.Join(os.Getenv("HOME"), ".local", "state")
+	}
+	dir := filepath.Join(stateHome, "ghostbrew")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "last-upgrade"), nil
+}
+
+// LastUpgrade returns the timestamp of the last recorded system
+// upgrade, or the zero time if none has been recorded yet.
+func LastUpgrade() (time.Time, error) {
+	path, err := statePath()
+	if err != nil {
+		return time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+}
+
+// RecordUpgrade stamps the current time as the last system upgrade, so
+// the next `news` check only shows entries published since.
+func RecordUpgrade() error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(time.Now().Format(time.RFC3339)), 0o644)
+}
@@ -0,0 +1,97 @@
+// Package pgp imports and verifies the PGP keys a PKGBUILD's
+// validpgpkeys=() array lists, so makepkg's own source verification
+// doesn't fail on a key the user's keyring has never seen.
+package pgp
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// KeyServers is the ordered list of keyservers tried when importing a
+// missing key. Populated from Config.KeyServers at startup; falls back
+// to DefaultKeyServer if left empty.
+var KeyServers []string
+
+// DefaultKeyServer is used when the user hasn't configured any.
+const DefaultKeyServer = "hkps://keyserver.ubuntu.com"
+
+// haveKey reports whether fingerprint fp is already present in the
+// user's keyring.
+func haveKey(fp string) bool {
+	return exec.Command("gpg", "--list-keys", fp).Run() == nil
+}
+
+// isTrusted reports whether fp has been locally signed/trusted rather
+// than merely present in the keyring.
+func isTrusted(fp string) bool {
+	out, err := exec.Command("gpg", "--list-keys", "--with-colons", fp).Output()
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if !strings.HasPrefix(line, "pub:") {
+			continue
+		}
+		fields := strings.Split(line, ":")
+		if len(fields) > 1 {
+			switch fields[1] {
+			case "f", "u": // full or ultimate trust
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func keyServers() []string {
+	if len(KeyServers) > 0 {
+		return KeyServers
+	}
+	return []string{DefaultKeyServer}
+}
+
+func importKey(fp string) error {
+	var lastErr error
+	for _, ks := range keyServers() {
+		cmd := exec.Command("gpg", "--recv-keys", "--keyserver", ks, fp)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			lastErr = fmt.Errorf("keyserver %s: %w: %s", ks, err, strings.TrimSpace(string(out)))
+			continue
+		}
+		return nil
+	}
+	return lastErr
+}
+
+// CheckKeys ensures every fingerprint in keys is present in the user's
+// keyring, importing any that are missing. Under noConfirm it imports
+// automatically; otherwise it prompts before each import. A key that is
+// present but not locally trusted produces a warning, not an error.
+// noConfirm is taken as a parameter rather than a package-level var so
+// concurrent callers (e.g. a parallel build queue) can't race on it.
+func CheckKeys(pkgbase string, keys []string, noConfirm bool) error {
+	for _, fp := range keys {
+		if haveKey(fp) {
+			if !isTrusted(fp) {
+				fmt.Printf("[PGP] Warning: key %s for %s is present but not trusted.\n", fp, pkgbase)
+			}
+			continue
+		}
+		if !noConfirm {
+			fmt.Printf("[PGP] %s requires unknown key %s. Import it? [Y/n] ", pkgbase, fp)
+			var reply string
+			fmt.Scanln(&reply)
+			reply = strings.ToLower(strings.TrimSpace(reply))
+			if reply == "n" || reply == "no" {
+				return fmt.Errorf("missing PGP key %s for %s", fp, pkgbase)
+			}
+		}
+		fmt.Printf("[PGP] Importing key %s for %s...\n", fp, pkgbase)
+		if err := importKey(fp); err != nil {
+			return fmt.Errorf("import key %s for %s: %w", fp, pkgbase, err)
+		}
+	}
+	return nil
+}
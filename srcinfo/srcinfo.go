@@ -0,0 +1,141 @@
+// Package srcinfo parses the .SRCINFO metadata format emitted by
+// `makepkg --printsrcinfo`, modelled on Morganamilo/go-srcinfo.
+//
+// .SRCINFO is a flat, line-oriented "key = value" format. Pkgbase-level
+// keys (pkgdesc, depends, ...) apply to every package built from the
+// PKGBUILD unless a later "pkgname = ..." section overrides them;
+// makedepends and checkdepends are pkgbase-wide only. Architecture-specific keys (e.g. makedepends_x86_64) are merged
+// into the same array as their unsuffixed counterpart.
+package srcinfo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Package describes one split package's pkgname/pkgdesc, as listed by a
+// "pkgname = ..." section. Per-pkgname dependency overrides (depends,
+// provides, ...) are deliberately not tracked here: ghostbrew resolves
+// dependencies per pkgbase via the AUR RPC's batched info (see the dep
+// package), not per split package, so parsing makepkg's per-pkgname
+// override rules for them would be dead weight.
+type Package struct {
+	Pkgname string
+	Pkgdesc string
+}
+
+// SRCINFO is the parsed contents of a .SRCINFO file.
+type SRCINFO struct {
+	Pkgbase      string
+	Pkgver       string
+	Pkgrel       string
+	Epoch        string
+	Arch         []string
+	Source       []string
+	ValidPGPKeys []string
+	MakeDepends  []string
+	CheckDepends []string
+	Packages     []Package
+}
+
+// arrayKeys lists the multi-value keys we merge across architecture
+// suffixes (e.g. "makedepends" and "makedepends_x86_64" both feed
+// MakeDepends).
+var arrayKeys = map[string]bool{
+	"arch": true, "source": true, "validpgpkeys": true,
+	"makedepends": true, "checkdepends": true,
+}
+
+// stripArch trims a trailing "_<arch>" suffix (e.g. "makedepends_x86_64"
+// -> "makedepends") so architecture-specific values fold into the same
+// key.
+func stripArch(key string) string {
+	if i := strings.LastIndex(key, "_"); i > 0 {
+		base := key[:i]
+		if arrayKeys[base] {
+			return base
+		}
+	}
+	return key
+}
+
+// Parse reads a .SRCINFO file and returns its pkgbase plus one Package
+// per "pkgname" section. makedepends/checkdepends are always pkgbase-
+// wide in a real PKGBUILD (makepkg doesn't let a split package override
+// them), so they're only collected while cur is nil.
+func Parse(r io.Reader) (*SRCINFO, error) {
+	info := &SRCINFO{}
+	base := Package{}
+	var cur *Package
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		val := strings.TrimSpace(parts[1])
+
+		if key == "pkgbase" {
+			info.Pkgbase = val
+			continue
+		}
+		if key == "pkgname" {
+			if cur != nil {
+				info.Packages = append(info.Packages, *cur)
+			}
+			pkg := base
+			pkg.Pkgname = val
+			cur = &pkg
+			continue
+		}
+
+		key = stripArch(key)
+		target := &base
+		if cur != nil {
+			target = cur
+		}
+
+		switch key {
+		case "pkgver":
+			info.Pkgver = val
+		case "pkgrel":
+			info.Pkgrel = val
+		case "epoch":
+			info.Epoch = val
+		case "pkgdesc":
+			target.Pkgdesc = val
+		case "arch":
+			info.Arch = append(info.Arch, val)
+		case "source":
+			info.Source = append(info.Source, val)
+		case "validpgpkeys":
+			info.ValidPGPKeys = append(info.ValidPGPKeys, val)
+		case "makedepends":
+			if cur == nil {
+				info.MakeDepends = append(info.MakeDepends, val)
+			}
+		case "checkdepends":
+			if cur == nil {
+				info.CheckDepends = append(info.CheckDepends, val)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("srcinfo: %w", err)
+	}
+	if cur != nil {
+		info.Packages = append(info.Packages, *cur)
+	}
+	if info.Pkgbase == "" {
+		return nil, fmt.Errorf("srcinfo: missing pkgbase")
+	}
+	return info, nil
+}
@@ -0,0 +1,92 @@
+package srcinfo
+
+import (
+	"strings"
+	"testing"
+)
+
+const sample = `pkgbase = foo
+	pkgver = 1.2.3
+	pkgrel = 1
+	epoch = 1
+	arch = x86_64
+	source = foo-1.2.3.tar.gz
+	validpgpkeys = DEADBEEF
+	makedepends = cmake
+	makedepends_i686 = nasm
+	checkdepends = check
+
+pkgname = foo
+	pkgdesc = The foo package
+
+pkgname = foo-doc
+	pkgdesc = Documentation for foo
+`
+
+func TestParse(t *testing.T) {
+	info, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if info.Pkgbase != "foo" {
+		t.Errorf("Pkgbase = %q, want foo", info.Pkgbase)
+	}
+	if info.Pkgver != "1.2.3" || info.Pkgrel != "1" || info.Epoch != "1" {
+		t.Errorf("Pkgver/Pkgrel/Epoch = %q/%q/%q, want 1.2.3/1/1", info.Pkgver, info.Pkgrel, info.Epoch)
+	}
+	if want := []string{"cmake", "nasm"}; !equalSlices(info.MakeDepends, want) {
+		t.Errorf("MakeDepends = %v, want %v (arch-suffixed key merged into base)", info.MakeDepends, want)
+	}
+	if want := []string{"check"}; !equalSlices(info.CheckDepends, want) {
+		t.Errorf("CheckDepends = %v, want %v", info.CheckDepends, want)
+	}
+	if len(info.Packages) != 2 {
+		t.Fatalf("len(Packages) = %d, want 2", len(info.Packages))
+	}
+	if info.Packages[0].Pkgname != "foo" || info.Packages[0].Pkgdesc != "The foo package" {
+		t.Errorf("Packages[0] = %+v", info.Packages[0])
+	}
+	if info.Packages[1].Pkgname != "foo-doc" || info.Packages[1].Pkgdesc != "Documentation for foo" {
+		t.Errorf("Packages[1] = %+v", info.Packages[1])
+	}
+}
+
+// TestParseMakeDependsIsPkgbaseWide confirms makedepends is only ever
+// collected before the first pkgname section, matching real
+// PKGBUILD/makepkg behavior (split packages can't override them).
+func TestParseMakeDependsIsPkgbaseWide(t *testing.T) {
+	const src = `pkgbase = foo
+	pkgver = 1.0
+	pkgrel = 1
+
+pkgname = foo
+	pkgdesc = first
+	makedepends = should-be-ignored
+`
+	info, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(info.MakeDepends) != 0 {
+		t.Errorf("MakeDepends = %v, want empty: makedepends under a pkgname section isn't valid makepkg syntax", info.MakeDepends)
+	}
+}
+
+func TestParseMissingPkgbase(t *testing.T) {
+	_, err := Parse(strings.NewReader("pkgver = 1.0\n"))
+	if err == nil {
+		t.Error("Parse did not error on a .SRCINFO with no pkgbase")
+	}
+}
+
+func equalSlices(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
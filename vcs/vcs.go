@@ -0,0 +1,212 @@
+// Package vcs tracks the upstream commit ghostbrew last built for
+// VCS-sourced AUR packages (-git/-svn/-hg/-bzr), mirroring the vcs.json
+// bookkeeping yay maintains so devel packages can be upgraded on new
+// commits even when their AUR-reported pkgver hasn't changed.
+package vcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// storeMu serializes the Load-mutate-Save sequence in RecordBuild, so
+// two packages finishing a build concurrently (a parallel build queue)
+// can't clobber each other's vcs.json update.
+var storeMu sync.Mutex
+
+// VCSInfo records one tracked VCS source entry for a package.
+type VCSInfo struct {
+	URL      string `json:"url"`
+	Branch   string `json:"branch"`
+	SHA      string `json:"sha"`
+	Protocol string `json:"protocol"` // git, svn, hg, or bzr
+}
+
+// Store maps pkgbase to the VCS sources recorded for its last build.
+type Store map[string][]VCSInfo
+
+// storePath returns $XDG_CACHE_HOME/ghostbrew/vcs.json.
+func storePath() (string, error) {
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		cacheHome = filepath.Join(os.Getenv("HOME"), ".cache")
+	}
+	dir := filepath.Join(cacheHome, "ghostbrew")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "vcs.json"), nil
+}
+
+// Load reads the persisted VCS store, returning an empty Store if it
+// doesn't exist yet.
+func Load() (Store, error) {
+	path, err := storePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Store{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	store := Store{}
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("vcs: parse %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// Save persists the store to disk.
+func (s Store) Save() error {
+	path, err := storePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// vcsProtocols maps the makepkg source prefix to our protocol name.
+var vcsProtocols = map[string]string{
+	"git+": "git",
+	"svn+": "svn",
+	"hg+":  "hg",
+	"bzr+": "bzr",
+}
+
+// ParseSources extracts the VCS entries from a .SRCINFO source=() array,
+// stripping the `git+`/`svn+`/`hg+`/`bzr+` prefix and any `#branch=...`
+// fragment makepkg uses to pin a non-default branch.
+func ParseSources(sources []string) []VCSInfo {
+	var infos []VCSInfo
+	for _, src := range sources {
+		// Drop an optional "name::" destination prefix.
+		if idx := strings.Index(src, "::"); idx != -1 {
+			src = src[idx+2:]
+		}
+		for prefix, proto := range vcsProtocols {
+			if !strings.HasPrefix(src, prefix) {
+				continue
+			}
+			rest := strings.TrimPrefix(src, prefix)
+			url := rest
+			branch := ""
+			if idx := strings.Index(rest, "#branch="); idx != -1 {
+				url = rest[:idx]
+				branch = rest[idx+len("#branch="):]
+			}
+			infos = append(infos, VCSInfo{URL: url, Branch: branch, Protocol: proto})
+			break
+		}
+	}
+	return infos
+}
+
+// ResolveHEAD resolves the current remote HEAD commit for a VCS
+// source, without cloning it locally.
+func ResolveHEAD(info VCSInfo) (string, error) {
+	switch info.Protocol {
+	case "git":
+		ref := "HEAD"
+		if info.Branch != "" {
+			ref = info.Branch
+		}
+		out, err := exec.Command("git", "ls-remote", info.URL, ref).Output()
+		if err != nil {
+			return "", fmt.Errorf("git ls-remote %s: %w", info.URL, err)
+		}
+		fields := strings.Fields(string(out))
+		if len(fields) == 0 {
+			return "", fmt.Errorf("git ls-remote %s: no output for %s", info.URL, ref)
+		}
+		return fields[0], nil
+	case "hg":
+		out, err := exec.Command("hg", "identify", info.URL).Output()
+		if err != nil {
+			return "", fmt.Errorf("hg identify %s: %w", info.URL, err)
+		}
+		fields := strings.Fields(string(out))
+		if len(fields) == 0 {
+			return "", fmt.Errorf("hg identify %s: no output", info.URL)
+		}
+		return fields[0], nil
+	default:
+		return "", fmt.Errorf("vcs: resolving HEAD for protocol %q not supported", info.Protocol)
+	}
+}
+
+// RecordBuild resolves the current remote HEAD for every VCS source of
+// pkgbase and persists it, so a future NeedsRebuild call can detect new
+// upstream commits. Non-VCS sources (and unsupported protocols) are
+// silently skipped.
+func RecordBuild(pkgbase string, sources []string) error {
+	infos := ParseSources(sources)
+	if len(infos) == 0 {
+		return nil
+	}
+	for i, info := range infos {
+		sha, err := ResolveHEAD(info)
+		if err != nil {
+			continue
+		}
+		infos[i].SHA = sha
+	}
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	store, err := Load()
+	if err != nil {
+		return err
+	}
+	store[pkgbase] = infos
+	return store.Save()
+}
+
+// NeedsRebuild reports whether any of pkgbase's tracked VCS sources have
+// moved since the last recorded build. tracked is false if RecordBuild
+// has never been called for pkgbase (e.g. it was installed by another
+// tool), in which case callers should fall back to their own check
+// rather than treat rebuild=false as "up to date".
+func NeedsRebuild(pkgbase string) (rebuild bool, tracked bool, err error) {
+	storeMu.Lock()
+	store, err := Load()
+	storeMu.Unlock()
+	if err != nil {
+		return false, false, err
+	}
+	sources, ok := store[pkgbase]
+	if !ok {
+		return false, false, nil
+	}
+	for _, info := range sources {
+		sha, err := ResolveHEAD(info)
+		if err != nil {
+			continue
+		}
+		if sha != info.SHA {
+			return true, true, nil
+		}
+	}
+	return false, true, nil
+}
+
+// IsDevel reports whether pkgname looks like a VCS/devel package by its
+// AUR naming convention (-git, -svn, -hg, -bzr, -darcs suffix).
+func IsDevel(pkgname string) bool {
+	for _, suffix := range []string{"-git", "-svn", "-hg", "-bzr", "-darcs"} {
+		if strings.HasSuffix(pkgname, suffix) {
+			return true
+		}
+	}
+	return false
+}